@@ -0,0 +1,33 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand/v2"
+	"os"
+
+	"github.com/voutilad/neobench/pkg/neobench"
+)
+
+func main() {
+	seed := flag.Uint64("seed", 0, "seed for the workload's PRNG; 0 picks a random seed")
+	clients := flag.Uint64("clients", 1, "number of clients to run, one after another, each drawing one unit of work")
+	flag.Parse()
+
+	s := *seed
+	if s == 0 {
+		s = rand.Uint64()
+	}
+
+	workload := neobench.NewWorkload(s, neobench.NewScripts(neobench.Script{Weight: 1}), nil)
+
+	for id := uint64(0); id < *clients; id++ {
+		client := workload.SplitClient(id)
+		if _, err := client.Next(); err != nil {
+			fmt.Fprintf(os.Stderr, "client %d: %v\n", id, err)
+			os.Exit(1)
+		}
+	}
+
+	fmt.Printf("ran %d client(s) with seed=%d\n", *clients, s)
+}
@@ -0,0 +1,109 @@
+// Package wchoose implements weighted random selection in O(1) time per pick
+// using Vose's alias method, rather than the O(log N) binary search over a
+// cumulative weight table.
+package wchoose
+
+import "math/rand/v2"
+
+// Chooser draws weighted random values of type T in O(1) time. Build one with
+// New and reuse it across picks; a Chooser is immutable once built and safe
+// for concurrent use by multiple goroutines as long as each caller supplies
+// its own randomness via Pick or PickRand.
+type Chooser[T any] struct {
+	items []T
+	// prob[i] is the probability of staying on item i once it has been
+	// picked uniformly at random; alias[i] is the item to fall back to
+	// otherwise. Together they encode Vose's alias table.
+	prob  []float64
+	alias []int
+}
+
+// New builds a Chooser over items, where weights[i] is the relative weight
+// of items[i]. weights must be the same length as items and each entry must
+// be >= 0, with at least one > 0.
+func New[T any](items []T, weights []float64) Chooser[T] {
+	n := len(items)
+	c := Chooser[T]{
+		items: items,
+		prob:  make([]float64, n),
+		alias: make([]int, n),
+	}
+	if n <= 1 {
+		if n == 1 {
+			c.prob[0] = 1
+		}
+		return c
+	}
+
+	var sum float64
+	for _, w := range weights {
+		sum += w
+	}
+	mean := sum / float64(n)
+
+	// scaled[i] is weights[i] normalized so the mean is 1; indices with
+	// scaled weight < 1 can't fill a whole slot on their own ("small"),
+	// the rest can cover for them ("large").
+	scaled := make([]float64, n)
+	small := make([]int, 0, n)
+	large := make([]int, 0, n)
+	for i, w := range weights {
+		scaled[i] = w / mean
+		if scaled[i] < 1 {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+
+	for len(small) > 0 && len(large) > 0 {
+		s := small[len(small)-1]
+		small = small[:len(small)-1]
+		l := large[len(large)-1]
+		large = large[:len(large)-1]
+
+		c.prob[s] = scaled[s]
+		c.alias[s] = l
+
+		scaled[l] -= 1 - scaled[s]
+		if scaled[l] < 1 {
+			small = append(small, l)
+		} else {
+			large = append(large, l)
+		}
+	}
+
+	// Leftovers only missed their partner due to floating point error;
+	// they're effectively weight 1 and always win their own slot.
+	for _, l := range large {
+		c.prob[l] = 1
+	}
+	for _, s := range small {
+		c.prob[s] = 1
+	}
+
+	return c
+}
+
+// Pick returns the item chosen by the pair (i, u), where i is a uniform
+// random index in [0, N) and u is a uniform random float in [0, 1). Callers
+// that already have their own source of randomness can use this directly;
+// Pick itself does no sampling and is safe for concurrent use.
+func (c Chooser[T]) Pick(i int, u float64) T {
+	if u < c.prob[i] {
+		return c.items[i]
+	}
+	return c.items[c.alias[i]]
+}
+
+// PickRand draws a weighted random item using r.
+func (c Chooser[T]) PickRand(r *rand.Rand) T {
+	return c.Pick(r.IntN(len(c.items)), r.Float64())
+}
+
+// PickGlobal draws a weighted random item using math/rand/v2's top-level,
+// goroutine-safe generator. Use this when many goroutines share a single
+// Chooser and handing out a private *rand.Rand per caller isn't practical.
+func (c Chooser[T]) PickGlobal() T {
+	return c.Pick(rand.IntN(len(c.items)), rand.Float64())
+}
@@ -0,0 +1,43 @@
+package wchoose
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+// TestChooserChiSquared checks that repeated Pick draws land on each item
+// with a frequency consistent with its weight, using a chi-squared
+// goodness-of-fit test against the expected distribution.
+func TestChooserChiSquared(t *testing.T) {
+	items := []int{0, 1, 2, 3}
+	weights := []float64{1, 2, 3, 4}
+	c := New(items, weights)
+
+	const draws = 200_000
+	counts := make([]int, len(items))
+	r := rand.New(rand.NewPCG(42, 1))
+	for i := 0; i < draws; i++ {
+		counts[c.PickRand(r)]++
+	}
+
+	var totalWeight float64
+	for _, w := range weights {
+		totalWeight += w
+	}
+
+	var chiSquared float64
+	for i, w := range weights {
+		expected := draws * w / totalWeight
+		diff := float64(counts[i]) - expected
+		chiSquared += diff * diff / expected
+	}
+
+	// Critical value for df = len(items)-1 = 3 at p = 0.001, i.e. we expect
+	// to fail this test on correctly-distributed draws at most 1 in 1000
+	// runs.
+	const criticalValue = 16.27
+	if chiSquared > criticalValue {
+		t.Fatalf("chi-squared statistic %.2f exceeds critical value %.2f (df=%d, p=0.001); counts=%v want weights=%v",
+			chiSquared, criticalValue, len(items)-1, counts, weights)
+	}
+}
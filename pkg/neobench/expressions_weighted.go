@@ -0,0 +1,176 @@
+package neobench
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/voutilad/neobench/internal/wchoose"
+)
+
+// WeightedChoice is one literal value and its relative weight in a
+// WeightedExpression, e.g. the `"read" => 7` half of
+// `weighted("read" => 7, "write" => 2)`.
+type WeightedChoice struct {
+	Value  interface{}
+	Weight float64
+}
+
+// WeightedExpression implements `weighted(v1 => w1, v2 => w2, ...)`: a
+// weighted choice over literal values. The alias-method chooser is built
+// once at parse time by NewWeightedExpression and drawn from in O(1) per
+// Eval using ctx.Rand.
+type WeightedExpression struct {
+	Choices []WeightedChoice
+
+	chooser wchoose.Chooser[interface{}]
+}
+
+func NewWeightedExpression(choices ...WeightedChoice) (WeightedExpression, error) {
+	if len(choices) == 0 {
+		return WeightedExpression{}, fmt.Errorf("weighted: must be given at least one choice")
+	}
+
+	values := make([]interface{}, len(choices))
+	weights := make([]float64, len(choices))
+	for i, c := range choices {
+		if c.Weight < 0 {
+			return WeightedExpression{}, fmt.Errorf("weighted: weight must be >= 0, got %v for %v", c.Weight, c.Value)
+		}
+		values[i] = c.Value
+		weights[i] = c.Weight
+	}
+
+	return WeightedExpression{
+		Choices: choices,
+		chooser: wchoose.New(values, weights),
+	}, nil
+}
+
+func (e WeightedExpression) Eval(ctx *ScriptContext) (interface{}, error) {
+	return e.chooser.PickRand(ctx.Rand), nil
+}
+
+// ZipfianExpression implements `zipfian(min, max, skew)`: an integer in
+// [min, max] drawn from a Zipf distribution with the given skew (theta),
+// using the generator from Gray et al., "Quickly Generating Billion-Record
+// Synthetic Databases" (as popularized by YCSB's ZipfianGenerator). skew
+// must be > 0 and != 1.
+type ZipfianExpression struct {
+	Min, Max int64
+	Skew     float64
+
+	n     float64
+	zetan float64
+}
+
+// zipfianMaxRange caps max-min+1: zipfianZeta is O(n), so building a
+// ZipfianExpression over an unbounded range can stall script construction
+// for seconds. Model large key spaces by scaling the drawn value instead of
+// widening the range.
+const zipfianMaxRange = 10_000_000
+
+func NewZipfianExpression(min, max int64, skew float64) (ZipfianExpression, error) {
+	if max < min {
+		return ZipfianExpression{}, fmt.Errorf("zipfian: max must be >= min, got min=%d max=%d", min, max)
+	}
+	if max-min+1 > zipfianMaxRange {
+		return ZipfianExpression{}, fmt.Errorf("zipfian: range (max-min+1) must be <= %d, got %d", zipfianMaxRange, max-min+1)
+	}
+	if skew <= 0 || skew == 1 {
+		return ZipfianExpression{}, fmt.Errorf("zipfian: skew must be > 0 and != 1, got %v", skew)
+	}
+
+	n := float64(max - min + 1)
+	return ZipfianExpression{
+		Min: min, Max: max, Skew: skew,
+		n:     n,
+		zetan: zipfianZeta(n, skew),
+	}, nil
+}
+
+func zipfianZeta(n, theta float64) float64 {
+	var sum float64
+	for i := 1.0; i <= n; i++ {
+		sum += 1 / math.Pow(i, theta)
+	}
+	return sum
+}
+
+func (e ZipfianExpression) Eval(ctx *ScriptContext) (interface{}, error) {
+	alpha := 1 / (1 - e.Skew)
+	eta := (1 - math.Pow(2/e.n, 1-e.Skew)) / (1 - zipfianZeta(2, e.Skew)/e.zetan)
+
+	u := ctx.Rand.Float64()
+	uz := u * e.zetan
+
+	var rank float64
+	switch {
+	case uz < 1:
+		rank = 1
+	case uz < 1+math.Pow(0.5, e.Skew):
+		rank = 2
+	default:
+		rank = 1 + e.n*math.Pow(eta*u-eta+1, alpha)
+	}
+
+	val := e.Min + int64(rank) - 1
+	if val > e.Max {
+		val = e.Max
+	}
+	if val < e.Min {
+		val = e.Min
+	}
+	return val, nil
+}
+
+// ExponentialExpression implements `exponential(min, max, rate)`: an
+// integer in [min, max] drawn from an exponential distribution, mapped onto
+// the range so rate controls the shape independently of how wide [min, max]
+// is: small rate (e.g. 1.0) is close to uniform, large rate (e.g. 10+)
+// concentrates draws near min. rate must be > 0.
+type ExponentialExpression struct {
+	Min, Max int64
+	Rate     float64
+}
+
+func NewExponentialExpression(min, max int64, rate float64) (ExponentialExpression, error) {
+	if max < min {
+		return ExponentialExpression{}, fmt.Errorf("exponential: max must be >= min, got min=%d max=%d", min, max)
+	}
+	if rate <= 0 {
+		return ExponentialExpression{}, fmt.Errorf("exponential: rate must be > 0, got %v", rate)
+	}
+
+	return ExponentialExpression{Min: min, Max: max, Rate: rate}, nil
+}
+
+func (e ExponentialExpression) Eval(ctx *ScriptContext) (interface{}, error) {
+	n := float64(e.Max - e.Min + 1)
+
+	// cut keeps the sampled uniform away from 0, so -log(uniform)/Rate is
+	// bounded to [0, 1] regardless of n -- the draw's shape depends only on
+	// Rate, not on how wide [min, max] is.
+	cut := math.Exp(-e.Rate)
+	uniform := cut + ctx.Rand.Float64()*(1-cut)
+	frac := -math.Log(uniform) / e.Rate
+
+	val := e.Min + int64(frac*n)
+	if val > e.Max {
+		val = e.Max
+	}
+	if val < e.Min {
+		val = e.Min
+	}
+	return val, nil
+}
+
+// GaussianExpression implements `gaussian(mean, stddev)`: a float64 drawn
+// from a normal distribution with the given mean and standard deviation.
+type GaussianExpression struct {
+	Mean   float64
+	Stddev float64
+}
+
+func (e GaussianExpression) Eval(ctx *ScriptContext) (interface{}, error) {
+	return ctx.Rand.NormFloat64()*e.Stddev + e.Mean, nil
+}
@@ -0,0 +1,146 @@
+package neobench
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+func testCtx(seed1, seed2 uint64) *ScriptContext {
+	return &ScriptContext{Rand: rand.New(rand.NewPCG(seed1, seed2))}
+}
+
+func TestWeightedExpressionValidation(t *testing.T) {
+	if _, err := NewWeightedExpression(); err == nil {
+		t.Fatal("expected error for zero choices")
+	}
+	if _, err := NewWeightedExpression(WeightedChoice{Value: "a", Weight: -1}); err == nil {
+		t.Fatal("expected error for negative weight")
+	}
+}
+
+func TestWeightedExpressionDistribution(t *testing.T) {
+	expr, err := NewWeightedExpression(
+		WeightedChoice{Value: "a", Weight: 1},
+		WeightedChoice{Value: "b", Weight: 3},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := testCtx(1, 2)
+	counts := map[string]int{}
+	const draws = 20_000
+	for i := 0; i < draws; i++ {
+		v, err := expr.Eval(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts[v.(string)]++
+	}
+
+	ratio := float64(counts["b"]) / float64(counts["a"])
+	if ratio < 2.5 || ratio > 3.5 {
+		t.Fatalf("expected b:a ratio near 3, got %.2f (counts=%v)", ratio, counts)
+	}
+}
+
+func TestZipfianExpressionValidation(t *testing.T) {
+	if _, err := NewZipfianExpression(10, 5, 0.99); err == nil {
+		t.Fatal("expected error for max < min")
+	}
+	if _, err := NewZipfianExpression(0, 10, 1); err == nil {
+		t.Fatal("expected error for skew == 1")
+	}
+	if _, err := NewZipfianExpression(0, 10, 0); err == nil {
+		t.Fatal("expected error for skew <= 0")
+	}
+	if _, err := NewZipfianExpression(0, zipfianMaxRange, 0.99); err == nil {
+		t.Fatal("expected error for range exceeding zipfianMaxRange")
+	}
+}
+
+func TestZipfianExpressionBounds(t *testing.T) {
+	expr, err := NewZipfianExpression(100, 200, 0.99)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := testCtx(3, 4)
+	for i := 0; i < 10_000; i++ {
+		v, err := expr.Eval(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n := v.(int64); n < 100 || n > 200 {
+			t.Fatalf("draw %d out of [100, 200]", n)
+		}
+	}
+}
+
+func TestExponentialExpressionValidation(t *testing.T) {
+	if _, err := NewExponentialExpression(10, 5, 1); err == nil {
+		t.Fatal("expected error for max < min")
+	}
+	if _, err := NewExponentialExpression(0, 10, 0); err == nil {
+		t.Fatal("expected error for rate <= 0")
+	}
+}
+
+func TestExponentialExpressionBoundsAndShape(t *testing.T) {
+	expr, err := NewExponentialExpression(0, 999, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := testCtx(5, 6)
+	const draws = 20_000
+	atMax := 0
+	for i := 0; i < draws; i++ {
+		v, err := expr.Eval(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		n := v.(int64)
+		if n < 0 || n > 999 {
+			t.Fatalf("draw %d out of [0, 999]", n)
+		}
+		if n == 999 {
+			atMax++
+		}
+	}
+
+	// A gentle rate should spread draws across the range instead of piling
+	// nearly all of them onto Max.
+	if frac := float64(atMax) / draws; frac > 0.2 {
+		t.Fatalf("rate=0.01 piled %.0f%% of draws onto Max, want a spread distribution", frac*100)
+	}
+}
+
+func TestGaussianExpressionDistribution(t *testing.T) {
+	expr := GaussianExpression{Mean: 50, Stddev: 10}
+	ctx := testCtx(7, 8)
+
+	const draws = 20_000
+	var sum, sumSq float64
+	for i := 0; i < draws; i++ {
+		v, err := expr.Eval(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f := v.(float64)
+		sum += f
+		sumSq += f * f
+	}
+
+	mean := sum / draws
+	variance := sumSq/draws - mean*mean
+	stddev := math.Sqrt(variance)
+
+	if math.Abs(mean-50) > 1 {
+		t.Fatalf("sample mean %.2f too far from 50", mean)
+	}
+	if math.Abs(stddev-10) > 1 {
+		t.Fatalf("sample stddev %.2f too far from 10", stddev)
+	}
+}
@@ -3,86 +3,104 @@ package neobench
 import (
 	"fmt"
 	"io"
-	"math/rand"
+	"math/rand/v2"
 	"os"
-	"sort"
 	"time"
+
+	"github.com/voutilad/neobench/internal/wchoose"
 )
 
+// clientSeedSalt is XORed into SeedLo to form the second PCG seed word of
+// every client's stream, so no client is seeded with the raw SeedLo word
+// (which NewWorkload sets to the user's seed unchanged).
+const clientSeedSalt uint64 = 0x9e3779b97f4a7c15
+
 type Workload struct {
 	// set on command line and built in
 	Variables map[string]interface{}
 
 	Scripts Scripts
 
-	Rand *rand.Rand
+	// SeedHi and SeedLo are the two 64-bit words derived from the
+	// workload's seed (see the --seed flag and NewWorkload); the Workload
+	// holds no generator of its own. SplitClient XORs them with a client id to deterministically derive
+	// that client's own independent stream, so runs are reproducible
+	// across machines, Go versions, and client counts regardless of
+	// goroutine scheduling.
+	SeedHi, SeedLo uint64
+}
+
+// NewWorkload builds a Workload seeded from a single scalar seed, such as
+// the one a user supplies via the --seed flag. The seed is split into
+// SeedHi/SeedLo with splitSeed so SplitClient has two decorrelated PCG
+// words to derive each client's stream from.
+func NewWorkload(seed uint64, scripts Scripts, variables map[string]interface{}) Workload {
+	hi, lo := splitSeed(seed)
+	return Workload{
+		Variables: variables,
+		Scripts:   scripts,
+		SeedHi:    hi,
+		SeedLo:    lo,
+	}
 }
 
-// Scripts in a workload, and utilities to draw a weighted random script
+// splitSeed derives two 64-bit words from a single scalar seed using one
+// splitmix64 step, so a user-supplied --seed doesn't feed the exact same
+// bits into both PCG words.
+func splitSeed(seed uint64) (hi, lo uint64) {
+	z := seed + 0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31), seed
+}
+
+// Scripts in a workload, and utilities to draw a weighted random script.
+// A Scripts value is immutable once built by NewScripts, so it's safe to
+// share a single Scripts across many client goroutines: Choose is safe to
+// call concurrently as long as each caller passes its own *rand.Rand, and
+// ChooseParallel is safe to call concurrently with no *rand.Rand at all.
 type Scripts struct {
 	// Scripts sorted by weight
 	Scripts []Script
-	// Lookup table for choice of scripts; one entry for each script, each entry records the cumulative
-	// weight of that script and all scripts before it in the array. See Choose() for details
-	WeightedLookup []int
-	// Sum of all weights in []Script
-	TotalWeight int
+
+	chooser wchoose.Chooser[Script]
 }
 
 func NewScripts(scripts ...Script) Scripts {
-	lookupTable := make([]int, len(scripts))
-	cumulativeWeight := 0
+	weights := make([]float64, len(scripts))
 	for i, script := range scripts {
-		cumulativeWeight += int(script.Weight)
-		lookupTable[i] = cumulativeWeight
+		weights[i] = float64(script.Weight)
 	}
 
 	return Scripts{
-		Scripts:        scripts,
-		WeightedLookup: lookupTable,
-		TotalWeight:    cumulativeWeight,
+		Scripts: scripts,
+		chooser: wchoose.New(scripts, weights),
 	}
 }
 
+// Choose draws a weighted random script in O(1) time using r, via the
+// alias-method table built by NewScripts.
 func (s *Scripts) Choose(r *rand.Rand) Script {
 	// Common case: There is just one script
 	if len(s.Scripts) == 1 {
 		return s.Scripts[0]
 	}
 
-	// How do you take the uniformly random number we get from rand, and convert it into a weighted choice of
-	// a script to use?
-	//
-	// Imagine that we create a segmented number line, each segment representing one script. The length of each
-	// segment is the weight of that script. So for three scripts, A@2, B@3, C@3, we create a line like:
-	//
-	//   0 1 2 3 4 5 6 7 8 9
-	//   [AA][BBBBBB][CCCCCC]
-	//
-	// Then we pick a number between 0 and the max of the number line (10 in the example). Say we get 4:
-	//
-	//   0 1 2 3 4 5 6 7 8 9
-	//   [AA][BBBBBB][CCCCCC]
-	//           ^
-	//
-	// The problem with this is that while it's easy visually to see which "item" we landed on, it's not obvious
-	// how to do it quickly on a computer. The solution used here is to maintain a lookup table with the cumulative
-	// weight at each segment, one entry per segment:
-	//
-	//   0 1 2 3 4 5 6 7 8 9
-	//   [AA][BBBBBB][CCCCCC]
-	//    +2     +3     +3    <-- weight of each segment
-	//    2      5      8     <-- lookup table value (eg. cumulation of weights)
-	//
-	// We can then do binary search into the lookup table, the index we get back is the segment our number fell on.
-
-	// 1: Pick a random number between 1 and the combined weight of all scripts
-	point := r.Intn(s.TotalWeight) + 1
-
-	// 2: Use binary search in the weighted lookup table to find the closest index for this weight
-	index := sort.SearchInts(s.WeightedLookup, point)
-
-	return s.Scripts[index]
+	return s.chooser.PickRand(r)
+}
+
+// ChooseParallel draws a weighted random script without a caller-supplied
+// *rand.Rand, using math/rand/v2's top-level generator instead. That
+// generator is safe for concurrent use by multiple goroutines, so unlike
+// Choose, ChooseParallel lets many worker goroutines share one Scripts
+// value with no per-caller *rand.Rand and no contention between them.
+func (s *Scripts) ChooseParallel() Script {
+	// Common case: There is just one script
+	if len(s.Scripts) == 1 {
+		return s.Scripts[0]
+	}
+
+	return s.chooser.PickGlobal()
 }
 
 type Script struct {
@@ -113,11 +131,16 @@ func (s *Script) Eval(ctx ScriptContext) (UnitOfWork, error) {
 	return uow, nil
 }
 
-func (s *Workload) NewClient() ClientWorkload {
+// SplitClient derives an independent, reproducible ClientWorkload for the
+// client identified by id. The derived stream depends only on
+// (SeedHi, SeedLo, id), so
+// it's the same regardless of what order clients are constructed in or how
+// many other clients exist, and can be reproduced by re-running a single id.
+func (s *Workload) SplitClient(id uint64) ClientWorkload {
 	return ClientWorkload{
 		Variables: s.Variables,
 		Scripts:   s.Scripts,
-		Rand:      rand.New(rand.NewSource(s.Rand.Int63())),
+		Rand:      rand.New(rand.NewPCG(s.SeedHi^id, s.SeedLo^clientSeedSalt)),
 		Stderr:    os.Stderr,
 	}
 }
@@ -205,4 +228,4 @@ func (c SleepCommand) Execute(ctx *ScriptContext, uow *UnitOfWork) error {
 	}
 	time.Sleep(time.Duration(sleepInt) * c.Unit)
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,45 @@
+package neobench
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"testing"
+)
+
+func benchScripts(n int) Scripts {
+	scripts := make([]Script, n)
+	for i := range scripts {
+		scripts[i] = Script{Weight: uint(i%10 + 1)}
+	}
+	return NewScripts(scripts...)
+}
+
+// BenchmarkChoose and BenchmarkChooseParallel compare single-goroutine vs
+// shared-goroutine pick throughput across script counts, to guard against
+// regressions in either path.
+func BenchmarkChoose(b *testing.B) {
+	for _, n := range []int{10, 100, 1_000, 10_000, 100_000, 1_000_000} {
+		b.Run(fmt.Sprintf("N=%d", n), func(b *testing.B) {
+			scripts := benchScripts(n)
+			r := rand.New(rand.NewPCG(1, 2))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				scripts.Choose(r)
+			}
+		})
+	}
+}
+
+func BenchmarkChooseParallel(b *testing.B) {
+	for _, n := range []int{10, 100, 1_000, 10_000, 100_000, 1_000_000} {
+		b.Run(fmt.Sprintf("N=%d", n), func(b *testing.B) {
+			scripts := benchScripts(n)
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					scripts.ChooseParallel()
+				}
+			})
+		})
+	}
+}